@@ -1,8 +1,6 @@
 package trackerclient
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -15,43 +13,135 @@ import (
 
 const defaultTrackerUrl = "https://legacy-api.arpa.li"
 
+// RetryPolicy controls how the underlying retryablehttp.Client retries
+// failed requests. A zero value for any field leaves retryablehttp's own
+// default for that field untouched.
+type RetryPolicy struct {
+	Max     int
+	WaitMin time.Duration
+	WaitMax time.Duration
+}
+
+// Logger is the structured logging interface TrackerClient reports to.
+// Callers can plug in their own log/metrics stack by setting
+// TrackerConfig.Logger; a stdLogger backed by the standard log package is
+// used if none is given.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+type stdLogger struct{}
+
+func (stdLogger) Debug(_ string, _ ...interface{}) {}
+func (stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	log.Printf("[INFO] %s %s", msg, keysAndValues)
+}
+func (stdLogger) Warn(msg string, keysAndValues ...interface{}) {
+	log.Printf("[WARN] %s %s", msg, keysAndValues)
+}
+func (stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	log.Printf("[ERROR] %s %s", msg, keysAndValues)
+}
+
+// RetryLogger adapts a Logger to the retryablehttp.LeveledLogger interface.
+type RetryLogger struct {
+	Logger Logger
+}
+
+func (that *RetryLogger) Debug(msg string, keysAndValues ...interface{}) {
+	that.Logger.Debug(msg, keysAndValues...)
+}
+func (that *RetryLogger) Info(msg string, keysAndValues ...interface{}) {
+	that.Logger.Info(msg, keysAndValues...)
+}
+func (that *RetryLogger) Warn(msg string, keysAndValues ...interface{}) {
+	that.Logger.Warn(msg, keysAndValues...)
+}
+func (that *RetryLogger) Error(msg string, keysAndValues ...interface{}) {
+	that.Logger.Error(msg, keysAndValues...)
+}
+
 type TrackerConfig struct {
 	Project        string
 	ProjectVersion string
 	TrackerUrl     string
 	Username       string
 	Password       string
-	httpClient     *retryablehttp.Client
+	// HTTPClient, if set, is used as the transport for tracker requests
+	// instead of the zero-value *http.Client retryablehttp creates by
+	// default. Use this to plug in a proxy, mTLS, or a test transport.
+	HTTPClient *http.Client
+	// Logger receives structured log events from the retry layer. Defaults
+	// to a logger backed by the standard log package.
+	Logger Logger
+	// RetryPolicy overrides retryablehttp's retry count and backoff bounds.
+	RetryPolicy    RetryPolicy
 	RequestTimeout time.Duration
+	httpClient     *retryablehttp.Client
+}
+
+// service is embedded by every service type and holds the TrackerClient it
+// was created from, giving each service access to the shared config and
+// request plumbing.
+type service struct {
+	client *TrackerClient
 }
 
 type TrackerClient struct {
 	trackerConfig *TrackerConfig
-}
 
-type Item struct {
-}
+	common service
 
-type RetryLogger struct {
-	retryablehttp.LeveledLogger
-}
+	// Items covers item lifecycle operations: requesting, marking done,
+	// releasing, failing, and heartbeating.
+	Items *ItemsService
+	// Project covers project metadata endpoints.
+	Project *ProjectService
+	// Stats covers tracker rate and queue depth endpoints.
+	Stats *StatsService
 
-func (that *RetryLogger) Debug(_ string, _ ...interface{}) {
-}
-func (that *RetryLogger) Info(msg string, keysAndValues ...interface{}) {
-	log.Printf("[INFO] %s %s", msg, keysAndValues)
+	// requestDeadline and heartbeatDeadline give the long-poll "request"
+	// calls and the "heartbeat" calls their own adjustable deadline,
+	// independent of trackerConfig.httpClient.HTTPClient.Timeout.
+	requestDeadline   *deadlineTimer
+	heartbeatDeadline *deadlineTimer
 }
-func (that *RetryLogger) Warn(msg string, keysAndValues ...interface{}) {
-	log.Printf("[WARN] %s %s", msg, keysAndValues)
+
+// SetRequestDeadline adjusts the deadline for in-flight and future
+// RequestItems calls. A zero Time clears the deadline.
+func (that *TrackerClient) SetRequestDeadline(t time.Time) {
+	that.requestDeadline.setDeadline(t)
 }
-func (that *RetryLogger) Error(msg string, keysAndValues ...interface{}) {
-	log.Printf("[ERROR] %s %s", msg, keysAndValues)
+
+// SetHeartbeatDeadline adjusts the deadline for in-flight and future
+// ItemHeartbeat calls. A zero Time clears the deadline.
+func (that *TrackerClient) SetHeartbeatDeadline(t time.Time) {
+	that.heartbeatDeadline.setDeadline(t)
 }
 
 func NewTrackerConfig(trackerConfig *TrackerConfig) (*TrackerClient, error) {
+	if trackerConfig.Logger == nil {
+		trackerConfig.Logger = stdLogger{}
+	}
 	trackerConfig.httpClient = retryablehttp.NewClient()
-	trackerConfig.httpClient.Logger = &RetryLogger{}
-	trackerConfig.httpClient.HTTPClient.Timeout = trackerConfig.RequestTimeout
+	trackerConfig.httpClient.Logger = &RetryLogger{Logger: trackerConfig.Logger}
+	if trackerConfig.HTTPClient != nil {
+		trackerConfig.httpClient.HTTPClient = trackerConfig.HTTPClient
+	} else {
+		trackerConfig.httpClient.HTTPClient.Timeout = trackerConfig.RequestTimeout
+	}
+	if trackerConfig.RetryPolicy.Max > 0 {
+		trackerConfig.httpClient.RetryMax = trackerConfig.RetryPolicy.Max
+	}
+	if trackerConfig.RetryPolicy.WaitMin > 0 {
+		trackerConfig.httpClient.RetryWaitMin = trackerConfig.RetryPolicy.WaitMin
+	}
+	if trackerConfig.RetryPolicy.WaitMax > 0 {
+		trackerConfig.httpClient.RetryWaitMax = trackerConfig.RetryPolicy.WaitMax
+	}
 	if trackerConfig.TrackerUrl == "" {
 		trackerConfig.TrackerUrl = defaultTrackerUrl
 	}
@@ -75,9 +165,16 @@ func NewTrackerConfig(trackerConfig *TrackerConfig) (*TrackerClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &TrackerClient{
-		trackerConfig: trackerConfig,
-	}, nil
+	client := &TrackerClient{
+		trackerConfig:     trackerConfig,
+		requestDeadline:   newDeadlineTimer(),
+		heartbeatDeadline: newDeadlineTimer(),
+	}
+	client.common.client = client
+	client.Items = (*ItemsService)(&client.common)
+	client.Project = (*ProjectService)(&client.common)
+	client.Stats = (*StatsService)(&client.common)
+	return client, nil
 }
 
 func (that *TrackerClient) newRequest(m string, p string, b any) (*retryablehttp.Request, error) {
@@ -96,124 +193,3 @@ func (that *TrackerClient) newRequest(m string, p string, b any) (*retryablehttp
 	}
 	return req, nil
 }
-
-type requestItemsRequest struct {
-	Downloader string `json:"downloader"`
-	APIVersion string `json:"api_version"`
-	Version    string `json:"version"`
-}
-
-type requestItemsResponse struct {
-	Items  []string `json:"items"`
-	Queues []string `json:"queues"`
-}
-
-func (that *TrackerClient) RequestItemsContext(ctx context.Context, limit uint64) ([]string, error) {
-	if limit < 1 {
-		return nil, fmt.Errorf("limit must be greater than 0")
-	}
-	p := "request"
-	if limit > 1 {
-		p = fmt.Sprintf("multi=%d/request", limit)
-	}
-	reqBody, err := json.Marshal(&requestItemsRequest{
-		Downloader: that.trackerConfig.Username,
-		APIVersion: "2",
-		Version:    that.trackerConfig.ProjectVersion,
-	})
-	if err != nil {
-		return nil, err
-	}
-	req, err := that.newRequest(http.MethodPost, p, reqBody)
-	if err != nil {
-		return nil, err
-	}
-	res, err := that.trackerConfig.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	if res.StatusCode == 404 || res.StatusCode == 204 {
-		return nil, ErrNoTasksAvailable
-	}
-	if res.StatusCode == 404 {
-		return nil, ErrNoSuchProject
-	}
-	if res.StatusCode >= 300 {
-		return nil, fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
-	}
-	var response requestItemsResponse
-	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
-		return nil, err
-	}
-	return response.Items, nil
-}
-
-func (that *TrackerClient) RequestItems(limit uint64) ([]string, error) {
-	return that.RequestItemsContext(context.Background(), limit)
-}
-
-func (that *TrackerClient) RequestItemContext(ctx context.Context) (item string, err error) {
-	items, err := that.RequestItemsContext(ctx, 1)
-	if err != nil {
-		return "", err
-	}
-	if len(items) == 0 {
-		return "", nil
-	}
-	return items[0], nil
-}
-
-func (that *TrackerClient) RequestItem() (item string, err error) {
-	return that.RequestItemContext(context.Background())
-}
-
-type itemsDoneRequest struct {
-	Downloader string            `json:"downloader"`
-	Version    string            `json:"version"`
-	Items      []string          `json:"items"`
-	Bytes      map[string]uint64 `json:"bytes"`
-}
-
-func (that *TrackerClient) ItemsDoneContext(ctx context.Context, items []string, bytes map[string]uint64) error {
-	if len(items) == 0 {
-		return nil
-	}
-	reqBody, err := json.Marshal(&itemsDoneRequest{
-		Downloader: that.trackerConfig.Username,
-		Version:    that.trackerConfig.ProjectVersion,
-		Items:      items,
-		Bytes:      bytes,
-	})
-	if err != nil {
-		return err
-	}
-	req, err := that.newRequest(http.MethodPost, "done", reqBody)
-	if err != nil {
-		return err
-	}
-	res, err := that.trackerConfig.httpClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	if res.StatusCode == 404 {
-		return ErrNoSuchProject
-	}
-	if res.StatusCode >= 300 {
-		return fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
-	}
-	return nil
-}
-
-func (that *TrackerClient) ItemsDone(items []string, bytes map[string]uint64) error {
-	return that.ItemsDoneContext(context.Background(), items, bytes)
-}
-
-func (that *TrackerClient) ItemDoneContext(ctx context.Context, item string) error {
-	return that.ItemsDoneContext(ctx, []string{item}, nil)
-}
-
-func (that *TrackerClient) ItemDone(item string) error {
-	return that.ItemDoneContext(context.Background(), item)
-}
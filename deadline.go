@@ -0,0 +1,88 @@
+package trackerclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// deadlineTimer pairs a cancel channel with the timer that will close it,
+// modelled on the deadline primitive used by netstack's gonet adapter. A
+// deadlineTimer starts with its channel open (no deadline set). Calling
+// setDeadline with a non-zero time arms a timer that closes the channel when
+// it fires; calling it again before that happens replaces the armed timer.
+// Once the channel has been closed, any further setDeadline call must swap
+// in a fresh channel so the deadlineTimer can be reused.
+type deadlineTimer struct {
+	mu       sync.Mutex
+	cancelCh chan struct{}
+	timer    *time.Timer
+	// closed records that cancelCh has already been closed (or is guaranteed
+	// to be closed imminently by a timer that already fired), independent of
+	// whether that.timer is still set. Any setDeadline call must swap in a
+	// fresh, open channel before reusing the deadlineTimer whenever closed is
+	// true.
+	closed bool
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{cancelCh: make(chan struct{})}
+}
+
+// setDeadline arms the timer to close the cancel channel at t. A zero t
+// clears any pending timer and leaves the channel open.
+func (that *deadlineTimer) setDeadline(t time.Time) {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	if that.timer != nil {
+		if !that.timer.Stop() {
+			// The timer already fired (or is about to): its callback owns
+			// closing the current cancelCh, so treat it as closed too.
+			that.closed = true
+		}
+		that.timer = nil
+	}
+	if that.closed {
+		// Swap in a fresh, open channel so the deadlineTimer can be reused,
+		// whether the previous channel was closed immediately (d <= 0 below)
+		// or by a timer that already fired.
+		that.cancelCh = make(chan struct{})
+		that.closed = false
+	}
+	if t.IsZero() {
+		return
+	}
+	d := time.Until(t)
+	if d <= 0 {
+		that.closed = true
+		close(that.cancelCh)
+		return
+	}
+	cancelCh := that.cancelCh
+	that.timer = time.AfterFunc(d, func() {
+		close(cancelCh)
+	})
+}
+
+func (that *deadlineTimer) channel() <-chan struct{} {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	return that.cancelCh
+}
+
+// withCancel derives a cancellable context from parent that is cancelled
+// either when parent is done or when the deadline's cancel channel closes,
+// letting callers adjust the deadline mid-flight without racing a single
+// global timeout.
+func (that *deadlineTimer) withCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	cancelCh := that.channel()
+	go func() {
+		select {
+		case <-cancelCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
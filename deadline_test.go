@@ -0,0 +1,76 @@
+package trackerclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func isOpen(t *testing.T, ch <-chan struct{}) bool {
+	t.Helper()
+	select {
+	case <-ch:
+		return false
+	default:
+		return true
+	}
+}
+
+func TestDeadlineTimerPastDeadlineThenClear(t *testing.T) {
+	dt := newDeadlineTimer()
+
+	dt.setDeadline(time.Now().Add(-time.Second))
+	if isOpen(t, dt.channel()) {
+		t.Fatal("expected channel closed immediately after a past deadline")
+	}
+
+	dt.setDeadline(time.Time{})
+	if !isOpen(t, dt.channel()) {
+		t.Fatal("expected channel open again after clearing the deadline")
+	}
+}
+
+func TestDeadlineTimerPastDeadlineThenFutureDeadline(t *testing.T) {
+	dt := newDeadlineTimer()
+
+	dt.setDeadline(time.Now().Add(-time.Second))
+
+	// Re-arming with a future deadline must not panic by closing the same
+	// already-closed channel a second time.
+	dt.setDeadline(time.Now().Add(20 * time.Millisecond))
+	if !isOpen(t, dt.channel()) {
+		t.Fatal("expected channel open right after re-arming a future deadline")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if isOpen(t, dt.channel()) {
+		t.Fatal("expected channel closed once the future deadline elapsed")
+	}
+}
+
+func TestDeadlineTimerConcurrentSetDeadlineAndWithCancel(t *testing.T) {
+	dt := newDeadlineTimer()
+	deadlines := []time.Duration{-time.Millisecond, 5 * time.Millisecond, 0}
+	const iterationsPerWorker = 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < iterationsPerWorker; n++ {
+				d := deadlines[(i+n)%len(deadlines)]
+				if d == 0 {
+					dt.setDeadline(time.Time{})
+				} else {
+					dt.setDeadline(time.Now().Add(d))
+				}
+				ctx, cancel := dt.withCancel(context.Background())
+				cancel()
+				<-ctx.Done()
+			}
+		}(i)
+	}
+	wg.Wait()
+}
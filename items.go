@@ -0,0 +1,406 @@
+package trackerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ItemsService covers item lifecycle operations: requesting, marking done,
+// releasing, failing, and heartbeating.
+type ItemsService service
+
+// Item is a unit of work handed out by the tracker, together with the queue
+// it was drawn from and when it was requested.
+type Item struct {
+	Name        string
+	Queue       string
+	RequestedAt time.Time
+}
+
+// groupItemNamesByQueue buckets item names by their Queue, returning the
+// distinct queues in first-seen order alongside the per-queue name lists.
+func groupItemNamesByQueue(items []Item) (queues []string, byQueue map[string][]string) {
+	byQueue = make(map[string][]string)
+	for _, item := range items {
+		if _, ok := byQueue[item.Queue]; !ok {
+			queues = append(queues, item.Queue)
+		}
+		byQueue[item.Queue] = append(byQueue[item.Queue], item.Name)
+	}
+	return queues, byQueue
+}
+
+func subsetBytes(bytes map[string]uint64, names []string) map[string]uint64 {
+	if bytes == nil {
+		return nil
+	}
+	subset := make(map[string]uint64, len(names))
+	for _, name := range names {
+		if b, ok := bytes[name]; ok {
+			subset[name] = b
+		}
+	}
+	return subset
+}
+
+type requestItemsRequest struct {
+	Downloader string `json:"downloader"`
+	APIVersion string `json:"api_version"`
+	Version    string `json:"version"`
+}
+
+type requestItemsResponse struct {
+	Items  []string `json:"items"`
+	Queues []string `json:"queues"`
+}
+
+func (that *ItemsService) requestItemsContext(ctx context.Context, limit uint64, queue string) ([]Item, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("limit must be greater than 0")
+	}
+	var segments []string
+	if limit > 1 {
+		segments = append(segments, fmt.Sprintf("multi=%d", limit))
+	}
+	if queue != "" {
+		segments = append(segments, fmt.Sprintf("queue=%s", queue))
+	}
+	segments = append(segments, "request")
+	p := strings.Join(segments, "/")
+	reqBody, err := json.Marshal(&requestItemsRequest{
+		Downloader: that.client.trackerConfig.Username,
+		APIVersion: "2",
+		Version:    that.client.trackerConfig.ProjectVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := that.client.newRequest(http.MethodPost, p, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	dctx, cancel := that.client.requestDeadline.withCancel(ctx)
+	defer cancel()
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(dctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return nil, ErrNoSuchProject
+	}
+	if res.StatusCode == 204 {
+		return nil, ErrNoTasksAvailable
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	var response requestItemsResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	requestedAt := time.Now()
+	items := make([]Item, len(response.Items))
+	for i, name := range response.Items {
+		itemQueue := queue
+		if itemQueue == "" && i < len(response.Queues) {
+			itemQueue = response.Queues[i]
+		}
+		items[i] = Item{Name: name, Queue: itemQueue, RequestedAt: requestedAt}
+	}
+	return items, nil
+}
+
+func (that *ItemsService) RequestItemsContext(ctx context.Context, limit uint64) ([]string, error) {
+	items, err := that.requestItemsContext(ctx, limit, "")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names, nil
+}
+
+func (that *ItemsService) RequestItems(limit uint64) ([]string, error) {
+	return that.RequestItemsContext(context.Background(), limit)
+}
+
+// RequestItemsFromQueueContext requests items from a specific tracker queue,
+// preserving the queue attribution on each returned Item.
+func (that *ItemsService) RequestItemsFromQueueContext(ctx context.Context, queue string, limit uint64) ([]Item, error) {
+	return that.requestItemsContext(ctx, limit, queue)
+}
+
+func (that *ItemsService) RequestItemsFromQueue(queue string, limit uint64) ([]Item, error) {
+	return that.RequestItemsFromQueueContext(context.Background(), queue, limit)
+}
+
+// RequestItemsFullContext requests items like RequestItemsContext but returns
+// the full Item, preserving whatever queue attribution the tracker reports.
+func (that *ItemsService) RequestItemsFullContext(ctx context.Context, limit uint64) ([]Item, error) {
+	return that.requestItemsContext(ctx, limit, "")
+}
+
+func (that *ItemsService) RequestItemsFull(limit uint64) ([]Item, error) {
+	return that.RequestItemsFullContext(context.Background(), limit)
+}
+
+func (that *ItemsService) RequestItemContext(ctx context.Context) (item string, err error) {
+	items, err := that.RequestItemsContext(ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", nil
+	}
+	return items[0], nil
+}
+
+func (that *ItemsService) RequestItem() (item string, err error) {
+	return that.RequestItemContext(context.Background())
+}
+
+type itemsDoneRequest struct {
+	Downloader string            `json:"downloader"`
+	Version    string            `json:"version"`
+	Items      []string          `json:"items"`
+	Bytes      map[string]uint64 `json:"bytes"`
+	Queue      string            `json:"queue,omitempty"`
+}
+
+func (that *ItemsService) itemsDoneContext(ctx context.Context, items []string, bytes map[string]uint64, queue string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	reqBody, err := json.Marshal(&itemsDoneRequest{
+		Downloader: that.client.trackerConfig.Username,
+		Version:    that.client.trackerConfig.ProjectVersion,
+		Items:      items,
+		Bytes:      bytes,
+		Queue:      queue,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := that.client.newRequest(http.MethodPost, "done", reqBody)
+	if err != nil {
+		return err
+	}
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return ErrNoSuchProject
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	return nil
+}
+
+func (that *ItemsService) ItemsDoneContext(ctx context.Context, items []string, bytes map[string]uint64) error {
+	return that.itemsDoneContext(ctx, items, bytes, "")
+}
+
+func (that *ItemsService) ItemsDone(items []string, bytes map[string]uint64) error {
+	return that.ItemsDoneContext(context.Background(), items, bytes)
+}
+
+func (that *ItemsService) ItemDoneContext(ctx context.Context, item string) error {
+	return that.ItemsDoneContext(ctx, []string{item}, nil)
+}
+
+func (that *ItemsService) ItemDone(item string) error {
+	return that.ItemDoneContext(context.Background(), item)
+}
+
+// ItemsDoneItemsContext reports a batch of typed Items as done, grouping them
+// by the queue they were requested from so each group's done call carries the
+// correct queue attribution.
+func (that *ItemsService) ItemsDoneItemsContext(ctx context.Context, items []Item, bytes map[string]uint64) error {
+	queues, byQueue := groupItemNamesByQueue(items)
+	for _, queue := range queues {
+		names := byQueue[queue]
+		if err := that.itemsDoneContext(ctx, names, subsetBytes(bytes, names), queue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (that *ItemsService) ItemsDoneItems(items []Item, bytes map[string]uint64) error {
+	return that.ItemsDoneItemsContext(context.Background(), items, bytes)
+}
+
+type itemsReleaseRequest struct {
+	Downloader string   `json:"downloader"`
+	Version    string   `json:"version"`
+	Items      []string `json:"items"`
+	Queue      string   `json:"queue,omitempty"`
+}
+
+func (that *ItemsService) itemsReleaseContext(ctx context.Context, items []string, queue string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	reqBody, err := json.Marshal(&itemsReleaseRequest{
+		Downloader: that.client.trackerConfig.Username,
+		Version:    that.client.trackerConfig.ProjectVersion,
+		Items:      items,
+		Queue:      queue,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := that.client.newRequest(http.MethodPost, "release", reqBody)
+	if err != nil {
+		return err
+	}
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return ErrNoSuchProject
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	return nil
+}
+
+func (that *ItemsService) ItemsReleaseContext(ctx context.Context, items []string) error {
+	return that.itemsReleaseContext(ctx, items, "")
+}
+
+func (that *ItemsService) ItemsRelease(items []string) error {
+	return that.ItemsReleaseContext(context.Background(), items)
+}
+
+func (that *ItemsService) ItemReleaseContext(ctx context.Context, item string) error {
+	return that.ItemsReleaseContext(ctx, []string{item})
+}
+
+func (that *ItemsService) ItemRelease(item string) error {
+	return that.ItemReleaseContext(context.Background(), item)
+}
+
+// ItemsReleaseItemsContext releases a batch of typed Items, grouping them by
+// the queue they were requested from so each group's release call carries
+// the correct queue attribution.
+func (that *ItemsService) ItemsReleaseItemsContext(ctx context.Context, items []Item) error {
+	queues, byQueue := groupItemNamesByQueue(items)
+	for _, queue := range queues {
+		if err := that.itemsReleaseContext(ctx, byQueue[queue], queue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (that *ItemsService) ItemsReleaseItems(items []Item) error {
+	return that.ItemsReleaseItemsContext(context.Background(), items)
+}
+
+type itemFailRequest struct {
+	Downloader string `json:"downloader"`
+	Version    string `json:"version"`
+	Item       string `json:"item"`
+	Reason     string `json:"reason"`
+	Queue      string `json:"queue,omitempty"`
+}
+
+func (that *ItemsService) itemFailContext(ctx context.Context, item string, reason string, queue string) error {
+	reqBody, err := json.Marshal(&itemFailRequest{
+		Downloader: that.client.trackerConfig.Username,
+		Version:    that.client.trackerConfig.ProjectVersion,
+		Item:       item,
+		Reason:     reason,
+		Queue:      queue,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := that.client.newRequest(http.MethodPost, "fail", reqBody)
+	if err != nil {
+		return err
+	}
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return ErrNoSuchProject
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	return nil
+}
+
+func (that *ItemsService) ItemFailContext(ctx context.Context, item string, reason string) error {
+	return that.itemFailContext(ctx, item, reason, "")
+}
+
+func (that *ItemsService) ItemFail(item string, reason string) error {
+	return that.ItemFailContext(context.Background(), item, reason)
+}
+
+// ItemFailItemContext fails a typed Item, carrying its queue attribution
+// through to the tracker.
+func (that *ItemsService) ItemFailItemContext(ctx context.Context, item Item, reason string) error {
+	return that.itemFailContext(ctx, item.Name, reason, item.Queue)
+}
+
+func (that *ItemsService) ItemFailItem(item Item, reason string) error {
+	return that.ItemFailItemContext(context.Background(), item, reason)
+}
+
+type itemHeartbeatRequest struct {
+	Downloader string `json:"downloader"`
+	Version    string `json:"version"`
+	Item       string `json:"item"`
+}
+
+func (that *ItemsService) ItemHeartbeatContext(ctx context.Context, item string) error {
+	reqBody, err := json.Marshal(&itemHeartbeatRequest{
+		Downloader: that.client.trackerConfig.Username,
+		Version:    that.client.trackerConfig.ProjectVersion,
+		Item:       item,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := that.client.newRequest(http.MethodPost, "heartbeat", reqBody)
+	if err != nil {
+		return err
+	}
+	dctx, cancel := that.client.heartbeatDeadline.withCancel(ctx)
+	defer cancel()
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(dctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return ErrNoSuchProject
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	return nil
+}
+
+func (that *ItemsService) ItemHeartbeat(item string) error {
+	return that.ItemHeartbeatContext(context.Background(), item)
+}
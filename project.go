@@ -0,0 +1,44 @@
+package trackerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ProjectService covers project metadata endpoints.
+type ProjectService service
+
+// ProjectInfo describes the tracker's view of the configured project.
+type ProjectInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+func (that *ProjectService) GetContext(ctx context.Context) (*ProjectInfo, error) {
+	req, err := that.client.newRequest(http.MethodGet, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return nil, ErrNoSuchProject
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	var info ProjectInfo
+	if err := json.NewDecoder(res.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (that *ProjectService) Get() (*ProjectInfo, error) {
+	return that.GetContext(context.Background())
+}
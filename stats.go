@@ -0,0 +1,45 @@
+package trackerclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// StatsService covers tracker rate and queue depth endpoints.
+type StatsService service
+
+// QueueStats reports the tracker's current throughput and backlog for the
+// configured project.
+type QueueStats struct {
+	QueueDepth uint64  `json:"queue_depth"`
+	Rate       float64 `json:"rate"`
+}
+
+func (that *StatsService) GetContext(ctx context.Context) (*QueueStats, error) {
+	req, err := that.client.newRequest(http.MethodGet, "stats", nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := that.client.trackerConfig.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == 404 {
+		return nil, ErrNoSuchProject
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s: %d", ErrInvalidTrackerResponse, res.StatusCode)
+	}
+	var stats QueueStats
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (that *StatsService) Get() (*QueueStats, error) {
+	return that.GetContext(context.Background())
+}
@@ -0,0 +1,311 @@
+package trackerclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ProcessFunc processes a single item fetched from the tracker and reports
+// how many bytes were downloaded for it. A non-nil error marks the item as
+// failed; wrap it with Fatal to report it permanently via ItemFail instead of
+// releasing it back to the queue for another worker to retry.
+type ProcessFunc func(ctx context.Context, item string) (bytesDownloaded uint64, err error)
+
+// WorkerConfig controls how a Worker drives the request/process/done loop.
+type WorkerConfig struct {
+	Concurrency       int
+	BatchSize         int
+	EmptyQueueBackoff time.Duration
+	MaxBackoff        time.Duration
+	HeartbeatInterval time.Duration
+}
+
+// FatalError marks a ProcessFunc error as permanent. Items that fail with a
+// FatalError are reported via ItemFail instead of being released back to the
+// tracker's queue.
+type FatalError struct {
+	Err error
+}
+
+func (e *FatalError) Error() string { return e.Err.Error() }
+func (e *FatalError) Unwrap() error { return e.Err }
+
+// Fatal wraps err so a Worker reports the item as permanently failed rather
+// than releasing it for another worker to retry.
+func Fatal(err error) error {
+	return &FatalError{Err: err}
+}
+
+func isFatal(err error) bool {
+	var fatalErr *FatalError
+	return errors.As(err, &fatalErr)
+}
+
+type workerResult struct {
+	item  string
+	bytes uint64
+	err   error
+}
+
+// Worker drives the request -> process -> done loop against a TrackerClient:
+// it fetches items in batches, dispatches them to a bounded pool of
+// goroutines running ProcessFunc, heartbeats in-flight items, coalesces
+// completed items into batched ItemsDone calls, and backs off on
+// ErrNoTasksAvailable.
+type Worker struct {
+	client  *TrackerClient
+	process ProcessFunc
+	config  WorkerConfig
+
+	mu         sync.Mutex
+	inFlightOf map[string]struct{}
+
+	inFlight      int64
+	doneCount     uint64
+	failedCount   uint64
+	releasedCount uint64
+	latencyTotal  int64
+	latencyCount  uint64
+}
+
+// NewWorker constructs a Worker. Zero-valued fields of config are replaced
+// with sane defaults (Concurrency and BatchSize of 1, EmptyQueueBackoff of
+// one second, MaxBackoff of five minutes).
+func NewWorker(client *TrackerClient, process ProcessFunc, config WorkerConfig) *Worker {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if config.BatchSize < 1 {
+		config.BatchSize = 1
+	}
+	if config.EmptyQueueBackoff <= 0 {
+		config.EmptyQueueBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+	return &Worker{
+		client:     client,
+		process:    process,
+		config:     config,
+		inFlightOf: make(map[string]struct{}),
+	}
+}
+
+// Run fetches and processes items until ctx is cancelled. It blocks until
+// every in-flight item has been processed and its outcome reported to the
+// tracker, so callers can rely on a clean shutdown once Run returns.
+func (that *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, that.config.Concurrency)
+	results := make(chan workerResult, that.config.Concurrency)
+	heartbeatDone := make(chan struct{})
+	collectDone := make(chan struct{})
+
+	heartbeatCtx, stopHeartbeats := context.WithCancel(context.Background())
+	defer stopHeartbeats()
+	go func() {
+		defer close(heartbeatDone)
+		that.runHeartbeats(heartbeatCtx)
+	}()
+	go func() {
+		defer close(collectDone)
+		that.collectResults(results)
+	}()
+
+	backoff := that.config.EmptyQueueBackoff
+dispatch:
+	for {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		default:
+		}
+
+		items, err := that.client.Items.RequestItemsContext(ctx, uint64(that.config.BatchSize))
+		if err != nil {
+			if !errors.Is(err, ErrNoTasksAvailable) {
+				that.client.trackerConfig.Logger.Warn("trackerclient: request failed, backing off", "error", err)
+			}
+			// Treat any tracker/network error, not just ErrNoTasksAvailable,
+			// as an empty queue and back off rather than spinning.
+			select {
+			case <-time.After(withJitter(backoff)):
+			case <-ctx.Done():
+				break dispatch
+			}
+			backoff = nextBackoff(backoff, that.config.MaxBackoff)
+			continue
+		}
+		if len(items) == 0 {
+			// A 200 response with an empty item list is an empty queue too;
+			// back off the same as ErrNoTasksAvailable instead of spinning.
+			select {
+			case <-time.After(withJitter(backoff)):
+			case <-ctx.Done():
+				break dispatch
+			}
+			backoff = nextBackoff(backoff, that.config.MaxBackoff)
+			continue
+		}
+		backoff = that.config.EmptyQueueBackoff
+
+		for i, item := range items {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				if err := that.client.Items.ItemsReleaseContext(context.Background(), items[i:]); err != nil {
+					that.client.trackerConfig.Logger.Error("trackerclient: failed to release undispatched items on shutdown", "error", err, "items", len(items[i:]))
+				} else {
+					atomic.AddUint64(&that.releasedCount, uint64(len(items[i:])))
+				}
+				break dispatch
+			}
+			that.markInFlight(item)
+			wg.Add(1)
+			go func(item string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				defer that.clearInFlight(item)
+				start := time.Now()
+				bytes, err := that.process(ctx, item)
+				that.recordLatency(time.Since(start))
+				results <- workerResult{item: item, bytes: bytes, err: err}
+			}(item)
+		}
+	}
+
+	wg.Wait()
+	close(results)
+	<-collectDone
+	stopHeartbeats()
+	<-heartbeatDone
+	return nil
+}
+
+func (that *Worker) collectResults(results <-chan workerResult) {
+	batch := make([]string, 0, that.config.BatchSize)
+	bytes := make(map[string]uint64, that.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := that.client.Items.ItemsDoneContext(context.Background(), batch, bytes); err != nil {
+			that.client.trackerConfig.Logger.Error("trackerclient: failed to report items done", "error", err, "items", len(batch))
+		} else {
+			atomic.AddUint64(&that.doneCount, uint64(len(batch)))
+		}
+		batch = batch[:0]
+		bytes = make(map[string]uint64, that.config.BatchSize)
+	}
+	for res := range results {
+		if res.err != nil {
+			if isFatal(res.err) {
+				if err := that.client.Items.ItemFailContext(context.Background(), res.item, res.err.Error()); err != nil {
+					that.client.trackerConfig.Logger.Error("trackerclient: failed to report item failed", "error", err, "item", res.item)
+				} else {
+					atomic.AddUint64(&that.failedCount, 1)
+				}
+			} else if err := that.client.Items.ItemReleaseContext(context.Background(), res.item); err != nil {
+				that.client.trackerConfig.Logger.Error("trackerclient: failed to release item", "error", err, "item", res.item)
+			} else {
+				atomic.AddUint64(&that.releasedCount, 1)
+			}
+			continue
+		}
+		batch = append(batch, res.item)
+		bytes[res.item] = res.bytes
+		if len(batch) >= that.config.BatchSize {
+			flush()
+		}
+	}
+	flush()
+}
+
+func (that *Worker) runHeartbeats(ctx context.Context) {
+	if that.config.HeartbeatInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(that.config.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, item := range that.inFlightItems() {
+				_ = that.client.Items.ItemHeartbeatContext(ctx, item)
+			}
+		}
+	}
+}
+
+func (that *Worker) inFlightItems() []string {
+	that.mu.Lock()
+	defer that.mu.Unlock()
+	items := make([]string, 0, len(that.inFlightOf))
+	for item := range that.inFlightOf {
+		items = append(items, item)
+	}
+	return items
+}
+
+func (that *Worker) markInFlight(item string) {
+	that.mu.Lock()
+	that.inFlightOf[item] = struct{}{}
+	that.mu.Unlock()
+	atomic.AddInt64(&that.inFlight, 1)
+}
+
+func (that *Worker) clearInFlight(item string) {
+	that.mu.Lock()
+	delete(that.inFlightOf, item)
+	that.mu.Unlock()
+	atomic.AddInt64(&that.inFlight, -1)
+}
+
+func (that *Worker) recordLatency(d time.Duration) {
+	atomic.AddInt64(&that.latencyTotal, int64(d))
+	atomic.AddUint64(&that.latencyCount, 1)
+}
+
+// ItemsDone returns the number of items successfully reported done so far.
+func (that *Worker) ItemsDone() uint64 { return atomic.LoadUint64(&that.doneCount) }
+
+// ItemsFailed returns the number of items reported permanently failed so far.
+func (that *Worker) ItemsFailed() uint64 { return atomic.LoadUint64(&that.failedCount) }
+
+// ItemsReleased returns the number of items released back to the queue so far.
+func (that *Worker) ItemsReleased() uint64 { return atomic.LoadUint64(&that.releasedCount) }
+
+// InFlight returns the number of items currently being processed.
+func (that *Worker) InFlight() int { return int(atomic.LoadInt64(&that.inFlight)) }
+
+// AverageLatency returns the mean time spent in ProcessFunc across all items
+// processed so far, or zero if none have completed yet.
+func (that *Worker) AverageLatency() time.Duration {
+	count := atomic.LoadUint64(&that.latencyCount)
+	if count == 0 {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&that.latencyTotal) / int64(count))
+}
+
+func nextBackoff(cur, max time.Duration) time.Duration {
+	next := cur * 2
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
@@ -0,0 +1,158 @@
+package trackerclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *TrackerClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewTrackerConfig(&TrackerConfig{
+		Project:        "proj",
+		ProjectVersion: "1",
+		Username:       "tester",
+		TrackerUrl:     server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewTrackerConfig: %v", err)
+	}
+	return client
+}
+
+// TestWorkerReleasesUndispatchedItemsOnCancel reproduces a batch where the
+// first item is still being processed (Concurrency: 1) when ctx is
+// cancelled. The remaining, never-dispatched items must be released back to
+// the tracker rather than silently dropped.
+func TestWorkerReleasesUndispatchedItemsOnCancel(t *testing.T) {
+	var releasedMu sync.Mutex
+	var released []string
+	var doneMu sync.Mutex
+	var done []string
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/request"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items":  []string{"item1", "item2", "item3"},
+				"queues": []string{},
+			})
+		case strings.HasSuffix(r.URL.Path, "/done"):
+			var body struct {
+				Items []string `json:"items"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			doneMu.Lock()
+			done = append(done, body.Items...)
+			doneMu.Unlock()
+		case strings.HasSuffix(r.URL.Path, "/release"):
+			var body struct {
+				Items []string `json:"items"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			releasedMu.Lock()
+			released = append(released, body.Items...)
+			releasedMu.Unlock()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+
+	client := newTestClient(t, handler)
+
+	started := make(chan struct{})
+	process := func(ctx context.Context, item string) (uint64, error) {
+		if item == "item1" {
+			close(started)
+			<-ctx.Done()
+		}
+		return 1, nil
+	}
+
+	worker := NewWorker(client, process, WorkerConfig{
+		Concurrency:       1,
+		BatchSize:         3,
+		EmptyQueueBackoff: time.Millisecond,
+		MaxBackoff:        10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	if err := worker.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	releasedMu.Lock()
+	gotReleased := append([]string(nil), released...)
+	releasedMu.Unlock()
+	doneMu.Lock()
+	gotDone := append([]string(nil), done...)
+	doneMu.Unlock()
+
+	wantReleased := map[string]bool{"item2": true, "item3": true}
+	if len(gotReleased) != len(wantReleased) {
+		t.Fatalf("released = %v, want exactly %v", gotReleased, wantReleased)
+	}
+	for _, item := range gotReleased {
+		if !wantReleased[item] {
+			t.Errorf("unexpected released item %q", item)
+		}
+	}
+
+	wantDone := map[string]bool{"item1": true}
+	if len(gotDone) != len(wantDone) {
+		t.Fatalf("done = %v, want exactly %v", gotDone, wantDone)
+	}
+}
+
+// TestWorkerBacksOffOnEmptyItems ensures a 200 response with a zero-length
+// item list is treated like ErrNoTasksAvailable and backed off, rather than
+// looping tight against the tracker.
+func TestWorkerBacksOffOnEmptyItems(t *testing.T) {
+	var requestCount int64
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/request") {
+			atomic.AddInt64(&requestCount, 1)
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []string{}, "queues": []string{}})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	client := newTestClient(t, handler)
+
+	worker := NewWorker(client, func(ctx context.Context, item string) (uint64, error) {
+		return 0, nil
+	}, WorkerConfig{
+		Concurrency:       1,
+		BatchSize:         1,
+		EmptyQueueBackoff: 20 * time.Millisecond,
+		MaxBackoff:        20 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Millisecond)
+	defer cancel()
+
+	if err := worker.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// withJitter waits between half and all of the 20ms backoff, so each
+	// wait is at least 10ms: at most 1 + 90/10 = 10 requests fit in 90ms.
+	if got := atomic.LoadInt64(&requestCount); got > 10 {
+		t.Fatalf("RequestItemsContext called %d times in 90ms with a 20ms backoff; dispatch loop is busy-looping on empty batches", got)
+	}
+}